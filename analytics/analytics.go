@@ -0,0 +1,175 @@
+package analytics
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+	"github.com/govindyagyasaini/golang-url-shortener/helpers"
+)
+
+// DB index dedicated to click analytics, kept separate from the shortened
+// URLs (0), rate-limit counters (1) and user accounts (2).
+const analyticsDB = 3
+
+// maxEvents caps how many recent hits are kept per short id.
+const maxEvents = 100
+
+// Event describes a single resolve of a short URL.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	IP        string    `json:"ip"`
+	Referer   string    `json:"referer"`
+	UserAgent string    `json:"user_agent"`
+	Country   string    `json:"country"`
+}
+
+// ReferrerCount is one entry of the top-referrers breakdown.
+type ReferrerCount struct {
+	Referer string `json:"referer"`
+	Count   int64  `json:"count"`
+}
+
+// Stats is the aggregated view returned by GET /analytics/:id.
+type Stats struct {
+	Total          int64            `json:"total"`
+	UniqueVisitors int64            `json:"unique_visitors"`
+	TopReferrers   []ReferrerCount  `json:"top_referrers"`
+	Daily          map[string]int64 `json:"daily"`
+	RecentEvents   []Event          `json:"recent_events"`
+}
+
+// Init creates the counters for a freshly minted short id, atomically, so
+// later reads never have to distinguish "zero clicks" from "unknown id".
+func Init(id string) error {
+	r := database.CreateClient(analyticsDB)
+	defer r.Close()
+
+	pipe := r.TxPipeline()
+	pipe.Set(database.Ctx, "clicks:"+id, 0, 0)
+	pipe.PFAdd(database.Ctx, "uniques:"+id)
+	_, err := pipe.Exec(database.Ctx)
+
+	return err
+}
+
+// RecordHit logs a single resolve of id, driven by the incoming request.
+func RecordHit(c *fiber.Ctx, id string) error {
+	r := database.CreateClient(analyticsDB)
+	defer r.Close()
+
+	event := Event{
+		Timestamp: time.Now(),
+		IP:        helpers.ClientIP(c),
+		Referer:   c.Get(fiber.HeaderReferer),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		Country:   country(c),
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	day := event.Timestamp.Format("2006-01-02")
+
+	pipe := r.Pipeline()
+	pipe.Incr(database.Ctx, "clicks:"+id)
+	pipe.LPush(database.Ctx, "events:"+id, payload)
+	pipe.LTrim(database.Ctx, "events:"+id, 0, maxEvents-1)
+	pipe.PFAdd(database.Ctx, "uniques:"+id, event.IP)
+	pipe.Incr(database.Ctx, "day:"+id+":"+day)
+	if event.Referer != "" {
+		pipe.HIncrBy(database.Ctx, "referrers:"+id, event.Referer, 1)
+	}
+	_, err = pipe.Exec(database.Ctx)
+
+	return err
+}
+
+// Aggregate returns the click metrics collected for a short id.
+func Aggregate(id string) (Stats, error) {
+	r := database.CreateClient(analyticsDB)
+	defer r.Close()
+
+	total, err := r.Get(database.Ctx, "clicks:"+id).Int64()
+	if err != nil && err != redis.Nil {
+		return Stats{}, err
+	}
+
+	unique, err := r.PFCount(database.Ctx, "uniques:"+id).Result()
+	if err != nil {
+		return Stats{}, err
+	}
+
+	referrers, err := r.HGetAll(database.Ctx, "referrers:"+id).Result()
+	if err != nil {
+		return Stats{}, err
+	}
+
+	top := make([]ReferrerCount, 0, len(referrers))
+	for ref, count := range referrers {
+		n, _ := strconv.ParseInt(count, 10, 64)
+		top = append(top, ReferrerCount{Referer: ref, Count: n})
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].Count > top[j].Count })
+
+	daily := map[string]int64{}
+	now := time.Now()
+	for i := 0; i < 30; i++ {
+		day := now.AddDate(0, 0, -i).Format("2006-01-02")
+		n, _ := r.Get(database.Ctx, "day:"+id+":"+day).Int64()
+		if n > 0 {
+			daily[day] = n
+		}
+	}
+
+	rawEvents, err := r.LRange(database.Ctx, "events:"+id, 0, maxEvents-1).Result()
+	if err != nil {
+		return Stats{}, err
+	}
+
+	events := make([]Event, 0, len(rawEvents))
+	for _, raw := range rawEvents {
+		var e Event
+		if json.Unmarshal([]byte(raw), &e) == nil {
+			events = append(events, e)
+		}
+	}
+
+	return Stats{
+		Total:          total,
+		UniqueVisitors: unique,
+		TopReferrers:   top,
+		Daily:          daily,
+		RecentEvents:   events,
+	}, nil
+}
+
+// country guesses the visitor's country from headers CDNs and browsers
+// commonly set, without pulling in a full GeoIP database.
+func country(c *fiber.Ctx) string {
+	if cf := c.Get("CF-IPCountry"); cf != "" {
+		return cf
+	}
+
+	lang := c.Get(fiber.HeaderAcceptLanguage)
+	if lang == "" {
+		return ""
+	}
+
+	// e.g. "en-US,en;q=0.9" -> "US"
+	primary := strings.SplitN(lang, ",", 2)[0]
+	parts := strings.SplitN(primary, "-", 2)
+	if len(parts) == 2 {
+		return strings.ToUpper(parts[1])
+	}
+
+	return ""
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/govindyagyasaini/golang-url-shortener/analytics"
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+)
+
+// GetAnalytics returns the click metrics collected for a short id. It's
+// restricted to the id's owner since the raw clicks include each visitor's
+// IP, user-agent, referrer and country.
+func GetAnalytics(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+	id := c.Params("id")
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	owner, err := r.Get(database.Ctx, "owner:"+id).Result()
+	if err != nil || owner != userID {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "short URL not found",
+		})
+	}
+
+	stats, err := analytics.Aggregate(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot fetch analytics",
+		})
+	}
+
+	return c.JSON(stats)
+}
@@ -0,0 +1,240 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/govindyagyasaini/golang-url-shortener/analytics"
+	"github.com/govindyagyasaini/golang-url-shortener/auth"
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+	"github.com/govindyagyasaini/golang-url-shortener/helpers"
+	"github.com/govindyagyasaini/golang-url-shortener/middleware/ratelimit"
+)
+
+type bulkItemRequest struct {
+	URL    string        `json:"url"`
+	Short  string        `json:"short"`
+	Expiry time.Duration `json:"expiry"`
+}
+
+type bulkItemResult struct {
+	URL   string `json:"url"`
+	Short string `json:"short,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkShorten shortens many URLs in a single request, either from a JSON
+// array body or a text/csv upload, storing them with a single Redis pipeline
+// round trip instead of one per item.
+func BulkShorten(c *fiber.Ctx) error {
+	items, err := parseBulkRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if max := bulkMax(); len(items) > max {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "batch exceeds BULK_MAX (" + strconv.Itoa(max) + ")",
+		})
+	}
+
+	userID := auth.UserID(c)
+	quotaKey := helpers.ClientIP(c)
+	if userID != "" {
+		quotaKey = "user:" + userID
+	}
+
+	rateClient := database.CreateClient(1)
+	defer rateClient.Close()
+
+	store := ratelimit.NewRedisStore(rateClient)
+
+	available, allowed, err := store.TakeN(quotaKey, len(items), apiQuota(), 30*time.Minute)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "rate limiter unavailable",
+		})
+	}
+
+	if !allowed {
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error":     "rate limit exceeded",
+			"shortfall": len(items) - available,
+		})
+	}
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	pipe := r.Pipeline()
+	results := make([]bulkItemResult, len(items))
+	createdIDs := make([]string, 0, len(items))
+	seenShorts := make(map[string]bool, len(items))
+
+	for i, item := range items {
+		results[i] = bulkItemResult{URL: item.URL}
+
+		if !govalidator.IsURL(item.URL) {
+			results[i].Error = "invalid URL"
+			continue
+		}
+
+		if !helpers.RemoveDomainError(item.URL) {
+			results[i].Error = "invalid domain"
+			continue
+		}
+
+		item.URL = helpers.EnforceHTTP(item.URL)
+
+		var id string
+		if item.Short == "" {
+			id, err = helpers.ShortID(r)
+			if err != nil {
+				results[i].Error = "cannot generate short URL"
+				continue
+			}
+		} else {
+			if !helpers.CustomShortPattern.MatchString(item.Short) {
+				results[i].Error = "custom short must match ^[0-9A-Za-z_-]{1,32}$"
+				continue
+			}
+
+			if seenShorts[item.Short] {
+				results[i].Error = "short URL already exists"
+				continue
+			}
+
+			if existing, _ := r.Get(database.Ctx, item.Short).Result(); existing != "" {
+				results[i].Error = "short URL already exists"
+				continue
+			}
+
+			seenShorts[item.Short] = true
+			id = item.Short
+		}
+
+		if item.Expiry == 0 {
+			item.Expiry = 24
+		}
+
+		pipe.Set(database.Ctx, id, item.URL, item.Expiry*time.Hour)
+
+		if userID != "" {
+			pipe.Set(database.Ctx, "owner:"+id, userID, item.Expiry*time.Hour)
+			pipe.SAdd(database.Ctx, "owner_urls:"+userID, id)
+		}
+
+		createdIDs = append(createdIDs, id)
+		results[i].URL = item.URL
+		results[i].Short = os.Getenv("DOMAIN") + "/" + id
+	}
+
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot save URLs",
+		})
+	}
+
+	for _, id := range createdIDs {
+		_ = analytics.Init(id)
+	}
+
+	return c.JSON(fiber.Map{
+		"results": results,
+	})
+}
+
+func parseBulkRequest(c *fiber.Ctx) ([]bulkItemRequest, error) {
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), "text/csv") {
+		return parseBulkCSV(c.Body())
+	}
+
+	var items []bulkItemRequest
+	if err := c.BodyParser(&items); err != nil {
+		return nil, errors.New("cannot parse JSON")
+	}
+
+	return items, nil
+}
+
+// parseBulkCSV expects a header row containing at least a "url" column, with
+// optional "short" and "expiry" (hours) columns.
+func parseBulkCSV(body []byte) ([]bulkItemRequest, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.New("cannot parse CSV")
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("empty CSV")
+	}
+
+	header := rows[0]
+	column := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	urlCol, shortCol, expiryCol := column("url"), column("short"), column("expiry")
+	if urlCol == -1 {
+		return nil, errors.New("CSV must have a url column")
+	}
+
+	items := make([]bulkItemRequest, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		var item bulkItemRequest
+		if urlCol < len(row) {
+			item.URL = row[urlCol]
+		}
+
+		if shortCol != -1 && shortCol < len(row) {
+			item.Short = row[shortCol]
+		}
+
+		if expiryCol != -1 && expiryCol < len(row) {
+			if hours, err := strconv.Atoi(row[expiryCol]); err == nil {
+				item.Expiry = time.Duration(hours)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func bulkMax() int {
+	n, err := strconv.Atoi(os.Getenv("BULK_MAX"))
+	if err != nil || n <= 0 {
+		return 100
+	}
+
+	return n
+}
+
+func apiQuota() int {
+	n, err := strconv.Atoi(os.Getenv("API_QUOTA"))
+	if err != nil || n <= 0 {
+		return 10
+	}
+
+	return n
+}
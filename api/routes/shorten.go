@@ -2,14 +2,13 @@ package routes
 
 import (
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/asaskevich/govalidator"
-	"github.com/go-redis/redis/v8"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 
+	"github.com/govindyagyasaini/golang-url-shortener/analytics"
+	"github.com/govindyagyasaini/golang-url-shortener/auth"
 	"github.com/govindyagyasaini/golang-url-shortener/database"
 	"github.com/govindyagyasaini/golang-url-shortener/helpers"
 )
@@ -36,23 +35,9 @@ func ShortenURL(c *fiber.Ctx) error {
 		})
 	}
 
-	// -------- RATE LIMITING (DB 1) --------
-	r2 := database.CreateClient(1)
-	defer r2.Close()
-
-	val, err := r2.Get(database.Ctx, c.IP()).Result()
-	if err == redis.Nil {
-		r2.Set(database.Ctx, c.IP(), os.Getenv("API_QUOTA"), 30*time.Minute)
-	} else {
-		valInt, _ := strconv.Atoi(val)
-		if valInt <= 0 {
-			ttl, _ := r2.TTL(database.Ctx, c.IP()).Result()
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error":            "rate limit exceeded",
-				"rate_limit_reset": ttl.Minutes(),
-			})
-		}
-	}
+	// Rate limiting now lives in the ratelimit middleware (see main.go); it
+	// has already run by the time we get here and left its verdict behind.
+	userID := auth.UserID(c)
 
 	// -------- URL VALIDATION --------
 	if !govalidator.IsURL(body.URL) {
@@ -69,29 +54,44 @@ func ShortenURL(c *fiber.Ctx) error {
 
 	body.URL = helpers.EnforceHTTP(body.URL)
 
+	// -------- STORE URL (DB 0) --------
+	r := database.CreateClient(0)
+	defer r.Close()
+
 	// -------- SHORT ID --------
+	// Auto-generated ids come from a collision-free counter, so they need no
+	// existence check; custom ids are user-chosen and can still collide.
 	var id string
 	if body.CustomShort == "" {
-		id = uuid.New().String()[:6]
+		generated, err := helpers.ShortID(r)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "cannot generate short URL",
+			})
+		}
+
+		id = generated
 	} else {
-		id = body.CustomShort
-	}
+		if !helpers.CustomShortPattern.MatchString(body.CustomShort) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "custom short must match ^[0-9A-Za-z_-]{1,32}$",
+			})
+		}
 
-	// -------- STORE URL (DB 0) --------
-	r := database.CreateClient(0)
-	defer r.Close()
+		id = body.CustomShort
 
-	if existing, _ := r.Get(database.Ctx, id).Result(); existing != "" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "short URL already exists",
-		})
+		if existing, _ := r.Get(database.Ctx, id).Result(); existing != "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "short URL already exists",
+			})
+		}
 	}
 
 	if body.Expiry == 0 {
 		body.Expiry = 24
 	}
 
-	err = r.Set(
+	err := r.Set(
 		database.Ctx,
 		id,
 		body.URL,
@@ -104,23 +104,25 @@ func ShortenURL(c *fiber.Ctx) error {
 		})
 	}
 
+	// Attribute the short URL to its owner so it can be listed/revoked later.
+	if userID != "" {
+		r.Set(database.Ctx, "owner:"+id, userID, body.Expiry*time.Hour)
+		r.SAdd(database.Ctx, "owner_urls:"+userID, id)
+	}
+
+	_ = analytics.Init(id)
+
 	// -------- RESPONSE --------
-	r2.Decr(database.Ctx, c.IP())
-	val, _ = r2.Get(database.Ctx, c.IP()).Result()
-	ttl, _ := r2.TTL(database.Ctx, c.IP()).Result()
+	remaining, _ := c.Locals("ratelimit_remaining").(int)
+	reset, _ := c.Locals("ratelimit_reset").(time.Duration)
 
 	resp := response{
 		URL:             body.URL,
 		CustomShort:     os.Getenv("DOMAIN") + "/" + id,
 		Expiry:          body.Expiry,
-		XRateRemaining:  atoi(val),
-		XRateLimitReset: ttl,
+		XRateRemaining:  remaining,
+		XRateLimitReset: reset,
 	}
 
 	return c.JSON(resp)
 }
-
-func atoi(s string) int {
-	i, _ := strconv.Atoi(s)
-	return i
-}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+)
+
+type ownedURL struct {
+	Short string `json:"short"`
+	URL   string `json:"url"`
+}
+
+// ListURLs returns every short URL owned by the authenticated caller.
+func ListURLs(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	ids, err := r.SMembers(database.Ctx, "owner_urls:"+userID).Result()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot fetch URLs",
+		})
+	}
+
+	urls := make([]ownedURL, 0, len(ids))
+	for _, id := range ids {
+		url, err := r.Get(database.Ctx, id).Result()
+		if err != nil {
+			continue
+		}
+
+		urls = append(urls, ownedURL{Short: id, URL: url})
+	}
+
+	return c.JSON(urls)
+}
+
+// DeleteURL revokes a short URL owned by the authenticated caller.
+func DeleteURL(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+	id := c.Params("id")
+
+	r := database.CreateClient(0)
+	defer r.Close()
+
+	owner, err := r.Get(database.Ctx, "owner:"+id).Result()
+	if err != nil || owner != userID {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "short URL not found",
+		})
+	}
+
+	pipe := r.Pipeline()
+	pipe.Del(database.Ctx, id)
+	pipe.Del(database.Ctx, "owner:"+id)
+	pipe.SRem(database.Ctx, "owner_urls:"+userID, id)
+
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot delete URL",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"short": id,
+	})
+}
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+)
+
+// DB index used for user accounts, kept separate from the shortened URLs (0)
+// and the rate-limit counters (1).
+const userDB = 2
+
+type registerRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type authResponse struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type claims struct {
+	UserID string `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// Register creates a new user account and returns a signed JWT.
+func Register(c *fiber.Ctx) error {
+	body := new(registerRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "cannot parse JSON",
+		})
+	}
+
+	if body.Email == "" || body.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "email and password are required",
+		})
+	}
+
+	r := database.CreateClient(userDB)
+	defer r.Close()
+
+	id := uuid.New().String()
+
+	// SetNX claims the email atomically: two concurrent registrations for
+	// the same address can't both pass a separate GET check and then
+	// overwrite each other's email:* pointer, orphaning one of the
+	// user:<id> hashes.
+	claimed, err := r.SetNX(database.Ctx, "email:"+body.Email, id, 0).Result()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot create account",
+		})
+	}
+
+	if !claimed {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "an account with this email already exists",
+		})
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(body.Password), bcrypt.DefaultCost)
+	if err != nil {
+		r.Del(database.Ctx, "email:"+body.Email)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot create account",
+		})
+	}
+
+	if err := r.HSet(database.Ctx, "user:"+id, map[string]interface{}{
+		"email":    body.Email,
+		"password": string(hash),
+	}).Err(); err != nil {
+		r.Del(database.Ctx, "email:"+body.Email)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot create account",
+		})
+	}
+
+	token, err := sign(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot sign token",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(authResponse{
+		ID:    id,
+		Email: body.Email,
+		Token: token,
+	})
+}
+
+// Login verifies credentials and returns a signed JWT.
+func Login(c *fiber.Ctx) error {
+	body := new(loginRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "cannot parse JSON",
+		})
+	}
+
+	r := database.CreateClient(userDB)
+	defer r.Close()
+
+	id, err := r.Get(database.Ctx, "email:"+body.Email).Result()
+	if err == redis.Nil || id == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid email or password",
+		})
+	}
+
+	hash, err := r.HGet(database.Ctx, "user:"+id, "password").Result()
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid email or password",
+		})
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(body.Password)) != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid email or password",
+		})
+	}
+
+	token, err := sign(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "cannot sign token",
+		})
+	}
+
+	return c.JSON(authResponse{
+		ID:    id,
+		Email: body.Email,
+		Token: token,
+	})
+}
+
+// Me returns the profile of the authenticated caller.
+func Me(c *fiber.Ctx) error {
+	id, _ := c.Locals("user_id").(string)
+
+	r := database.CreateClient(userDB)
+	defer r.Close()
+
+	email, err := r.HGet(database.Ctx, "user:"+id, "email").Result()
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "user not found",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"id":    id,
+		"email": email,
+	})
+}
+
+// Protected guards a route, requiring a valid "Authorization: Bearer <token>"
+// header and exposing the caller's id to handlers via c.Locals("user_id").
+func Protected() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := verify(bearerToken(c))
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "missing or invalid token",
+			})
+		}
+
+		c.Locals("user_id", id)
+		return c.Next()
+	}
+}
+
+// UserID returns the caller's id when the request carries a valid token, and
+// "" otherwise. It never fails the request, so routes that support both
+// authenticated and anonymous access (like ShortenURL) can call it directly.
+func UserID(c *fiber.Ctx) string {
+	id, err := verify(bearerToken(c))
+	if err != nil {
+		return ""
+	}
+
+	return id
+}
+
+func bearerToken(c *fiber.Ctx) string {
+	header := c.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+
+	return strings.TrimPrefix(header, "Bearer ")
+}
+
+func sign(userID string) (string, error) {
+	seconds, err := strconv.Atoi(os.Getenv("JWT_EXPIRED_SECOND"))
+	if err != nil {
+		seconds = 3600
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(seconds) * time.Second)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	})
+
+	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+}
+
+func verify(tokenString string) (string, error) {
+	if tokenString == "" {
+		return "", jwt.ErrTokenMalformed
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &claims{}, func(t *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil || !token.Valid {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	c, ok := token.Claims.(*claims)
+	if !ok {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	return c.UserID, nil
+}
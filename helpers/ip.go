@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientIP returns the real client IP for c. When the direct peer is a
+// configured trusted proxy (see fiber.Config.TrustedProxies in main.go) it
+// honors X-Forwarded-For, X-Real-IP and CF-Connecting-IP, in that order;
+// otherwise those headers are attacker-controlled and it falls back to the
+// socket peer.
+func ClientIP(c *fiber.Ctx) string {
+	// Fiber's IsProxyTrusted() returns true whenever
+	// EnableTrustedProxyCheck is off, which is the default for a
+	// deployment that never set TRUSTED_PROXIES — so it can't be used
+	// alone to gate trust in forwarded headers. Require the check to be
+	// enabled too.
+	if !c.App().Config().EnableTrustedProxyCheck || !c.IsProxyTrusted() {
+		return c.IP()
+	}
+
+	if ip := firstForwarded(c.Get(fiber.HeaderXForwardedFor)); ip != "" {
+		return ip
+	}
+
+	if ip := c.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+
+	if ip := c.Get("CF-Connecting-IP"); ip != "" {
+		return ip
+	}
+
+	return c.IP()
+}
+
+func firstForwarded(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.Split(header, ",")[0])
+}
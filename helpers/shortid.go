@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"os"
+	"regexp"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+)
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	shortIDMinLen = 6
+
+	// feistelBits is the total width of the permuted counter: 21 bits per
+	// half, wide enough that the sequential Redis counter never wraps in
+	// practice while still encoding to a short, uniform-looking base62 id.
+	feistelBits   = 42
+	feistelHalf   = feistelBits / 2
+	feistelRounds = 4
+)
+
+// CustomShortPattern validates a client-supplied short string.
+var CustomShortPattern = regexp.MustCompile(`^[0-9A-Za-z_-]{1,32}$`)
+
+// ShortID returns the next collision-free short id. It draws from a
+// monotonically increasing Redis counter and runs it through a keyed
+// Feistel permutation before base62-encoding it, so ids stay unguessable and
+// non-enumerable even though the underlying counter is sequential. Because
+// the permutation is a bijection on feistelBits-bit integers, two calls can
+// never produce the same id and no post-generation existence check is
+// needed for auto-generated ids.
+func ShortID(r *redis.Client) (string, error) {
+	seq, err := r.Incr(database.Ctx, "shortid:seq").Result()
+	if err != nil {
+		return "", err
+	}
+
+	permuted := feistelEncrypt(uint64(seq) & (1<<feistelBits - 1))
+
+	return base62Encode(permuted, shortIDMinLen), nil
+}
+
+// feistelEncrypt permutes x (a feistelBits-bit integer) using a
+// HMAC-SHA256-keyed Feistel network seeded from the SHORTID_SECRET env var.
+func feistelEncrypt(x uint64) uint64 {
+	left := uint32(x >> feistelHalf)
+	right := uint32(x & (1<<feistelHalf - 1))
+
+	for round := 0; round < feistelRounds; round++ {
+		left, right = right, left^feistelRoundFunc(right, round)
+	}
+
+	return uint64(left)<<feistelHalf | uint64(right)
+}
+
+func feistelRoundFunc(half uint32, round int) uint32 {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("SHORTID_SECRET")))
+
+	var buf [5]byte
+	binary.BigEndian.PutUint32(buf[:4], half)
+	buf[4] = byte(round)
+	mac.Write(buf[:])
+
+	sum := mac.Sum(nil)
+
+	return binary.BigEndian.Uint32(sum[:4]) & (1<<feistelHalf - 1)
+}
+
+func base62Encode(n uint64, minLen int) string {
+	if n == 0 {
+		return padLeft("0", minLen)
+	}
+
+	base := uint64(len(base62Alphabet))
+
+	var buf []byte
+	for n > 0 {
+		buf = append([]byte{base62Alphabet[n%base]}, buf...)
+		n /= base
+	}
+
+	return padLeft(string(buf), minLen)
+}
+
+func padLeft(s string, minLen int) string {
+	for len(s) < minLen {
+		s = "0" + s
+	}
+
+	return s
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/joho/godotenv"
+
+	"github.com/govindyagyasaini/golang-url-shortener/api/routes"
+	"github.com/govindyagyasaini/golang-url-shortener/auth"
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+	"github.com/govindyagyasaini/golang-url-shortener/helpers"
+	"github.com/govindyagyasaini/golang-url-shortener/middleware/ratelimit"
+)
+
+// userAwareKey keys the rate limiter on the caller's user id when they're
+// authenticated, falling back to their (trusted-proxy-aware) IP otherwise.
+func userAwareKey(c *fiber.Ctx) string {
+	if userID := auth.UserID(c); userID != "" {
+		return "user:" + userID
+	}
+
+	return helpers.ClientIP(c)
+}
+
+// trustedProxies reads TRUSTED_PROXIES as a comma-separated list of CIDRs or
+// IPs (e.g. "10.0.0.0/8,172.16.0.0/12") that are allowed to set
+// X-Forwarded-For / X-Real-IP / CF-Connecting-IP for a request.
+func trustedProxies() []string {
+	raw := os.Getenv("TRUSTED_PROXIES")
+	if raw == "" {
+		return nil
+	}
+
+	return strings.Split(raw, ",")
+}
+
+func envQuota(name string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return fallback
+	}
+
+	return n
+}
+
+func setupRoutes(app *fiber.App) {
+	shortenLimit := ratelimit.New(ratelimit.Config{
+		Max:          envQuota("API_QUOTA", 10),
+		Expiration:   30 * time.Minute,
+		KeyGenerator: userAwareKey,
+		Store:        ratelimit.NewRedisStore(database.CreateClient(1)),
+	})
+
+	resolveLimit := ratelimit.New(ratelimit.Config{
+		Max:          envQuota("RESOLVE_QUOTA", 100),
+		Expiration:   30 * time.Minute,
+		KeyGenerator: userAwareKey,
+		Store:        ratelimit.NewRedisStore(database.CreateClient(1)),
+	})
+
+	app.Get("/:url", resolveLimit, routes.ResolveURL)
+	app.Post("/api/v1", shortenLimit, routes.ShortenURL)
+	// Bulk debits its quota itself (N units atomically), so it isn't gated by
+	// the per-request shortenLimit middleware.
+	app.Post("/api/v1/bulk", routes.BulkShorten)
+	app.Get("/analytics/:id", auth.Protected(), routes.GetAnalytics)
+
+	app.Post("/api/v1/auth/register", auth.Register)
+	app.Post("/api/v1/auth/login", auth.Login)
+	app.Get("/api/v1/auth/me", auth.Protected(), auth.Me)
+
+	app.Get("/urls", auth.Protected(), routes.ListURLs)
+	app.Delete("/urls/:id", auth.Protected(), routes.DeleteURL)
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Print("Error loading .env file")
+	}
+
+	proxies := trustedProxies()
+
+	cfg := fiber.Config{
+		EnableTrustedProxyCheck: len(proxies) > 0,
+		TrustedProxies:          proxies,
+	}
+
+	// Fiber trusts every caller's forwarded headers whenever the trusted-proxy
+	// check is disabled, so ProxyHeader must only be set once we actually have
+	// a trusted set to check against — otherwise an unconfigured deployment
+	// (the out-of-the-box default) would honor X-Forwarded-For from anyone.
+	if len(proxies) > 0 {
+		cfg.ProxyHeader = fiber.HeaderXForwardedFor
+	}
+
+	app := fiber.New(cfg)
+	app.Use(logger.New())
+
+	setupRoutes(app)
+
+	log.Fatal(app.Listen(os.Getenv("APP_PORT")))
+}
@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process fixed-window counter: each key gets max units
+// per expiration window, and the full quota comes back only once the window
+// has expired; spent units are not refilled gradually within a window. It's
+// the default store, handy for tests and single-instance deployments where
+// there's no Redis to share counters across processes.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	remaining int
+	resetAt   time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryStore) Take(key string, max int, expiration time.Duration) (int, time.Duration, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	b, ok := s.buckets[key]
+	if !ok || now.After(b.resetAt) {
+		b = &bucket{remaining: max, resetAt: now.Add(expiration)}
+		s.buckets[key] = b
+	}
+
+	if b.remaining <= 0 {
+		return 0, time.Until(b.resetAt), false, nil
+	}
+
+	b.remaining--
+
+	return b.remaining, time.Until(b.resetAt), true, nil
+}
@@ -0,0 +1,103 @@
+// Package ratelimit is a pluggable Fiber rate-limiting middleware. It is
+// deliberately decoupled from any particular backend: a MemoryStore is handed
+// out by default (cheap, good enough for tests and single instances) while a
+// RedisStore is available for multi-instance deployments.
+package ratelimit
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/govindyagyasaini/golang-url-shortener/helpers"
+)
+
+// Store persists rate-limit counters behind Config.
+type Store interface {
+	// Take consumes one unit from key's bucket, creating it with the given
+	// max/expiration if it doesn't exist yet. It reports the quota remaining
+	// after this request, the time until the window resets, and whether the
+	// request is allowed to proceed.
+	Take(key string, max int, expiration time.Duration) (remaining int, reset time.Duration, allowed bool, err error)
+}
+
+// Config configures the rate-limiting middleware.
+type Config struct {
+	// Max is the number of requests allowed per Expiration window (the burst).
+	Max int
+	// Expiration is how long a caller's quota window lasts before it resets.
+	Expiration time.Duration
+	// KeyGenerator extracts the bucket key for a request. Defaults to
+	// helpers.ClientIP(c).
+	KeyGenerator func(c *fiber.Ctx) string
+	// LimitReached is invoked instead of c.Next() once a caller's quota is
+	// exhausted. Defaults to a 429 JSON response.
+	LimitReached fiber.Handler
+	// Store persists quota counters. Defaults to a process-local MemoryStore.
+	Store Store
+}
+
+func configDefault(cfg Config) Config {
+	if cfg.Max <= 0 {
+		cfg.Max = 20
+	}
+
+	if cfg.Expiration <= 0 {
+		cfg.Expiration = 30 * time.Minute
+	}
+
+	if cfg.KeyGenerator == nil {
+		cfg.KeyGenerator = func(c *fiber.Ctx) string {
+			return helpers.ClientIP(c)
+		}
+	}
+
+	if cfg.LimitReached == nil {
+		cfg.LimitReached = func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "rate limit exceeded",
+			})
+		}
+	}
+
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+
+	return cfg
+}
+
+// New returns a rate-limiting middleware built from cfg. Whenever the store
+// answers, it sets the X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset headers, on allowed and rejected requests alike, and
+// exposes the same values via c.Locals("ratelimit_remaining") /
+// c.Locals("ratelimit_reset") for handlers that still echo them in the body.
+// When the store returns an error the request fails with a 500 and no
+// rate-limit headers are set.
+func New(cfg Config) fiber.Handler {
+	cfg = configDefault(cfg)
+
+	return func(c *fiber.Ctx) error {
+		key := cfg.KeyGenerator(c)
+
+		remaining, reset, allowed, err := cfg.Store.Take(key, cfg.Max, cfg.Expiration)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "rate limiter unavailable",
+			})
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(cfg.Max))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(int(reset.Seconds())))
+		c.Locals("ratelimit_remaining", remaining)
+		c.Locals("ratelimit_reset", reset)
+
+		if !allowed {
+			return cfg.LimitReached(c)
+		}
+
+		return c.Next()
+	}
+}
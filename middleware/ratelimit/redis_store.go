@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/govindyagyasaini/golang-url-shortener/database"
+)
+
+// RedisStore is a fixed-window rate limiter backed by Redis, shared across
+// every instance of the service: a key's counter starts at max with a TTL of
+// the expiration window, and the quota is restored only when that key
+// expires; there is no gradual refill. Take and TakeN each run as a single Lua
+// script so the read-compare-decrement is atomic: two concurrent callers for
+// the same key can never both observe quota that only one of them is
+// entitled to.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore wraps an existing Redis client for use as a Store.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+var takeScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current == false then
+	redis.call("SET", KEYS[1], ARGV[1] - 1, "EX", ARGV[2])
+	return {ARGV[1] - 1, ARGV[2], 1}
+end
+
+current = tonumber(current)
+if current <= 0 then
+	return {0, redis.call("TTL", KEYS[1]), 0}
+end
+
+local remaining = redis.call("DECR", KEYS[1])
+return {remaining, redis.call("TTL", KEYS[1]), 1}
+`)
+
+func (s *RedisStore) Take(key string, max int, expiration time.Duration) (int, time.Duration, bool, error) {
+	res, err := takeScript.Run(database.Ctx, s.client, []string{key}, max, int(expiration.Seconds())).Result()
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	remaining, ttl, allowed, err := unpackTriple(res)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	return remaining, time.Duration(ttl) * time.Second, allowed, nil
+}
+
+var takeNScript = redis.NewScript(`
+local n, max = tonumber(ARGV[1]), tonumber(ARGV[2])
+local current = redis.call("GET", KEYS[1])
+
+if current == false then
+	if n > max then
+		return {max, 0}
+	end
+
+	local remaining = max - n
+	redis.call("SET", KEYS[1], remaining, "EX", ARGV[3])
+	return {remaining, 1}
+end
+
+current = tonumber(current)
+if current < n then
+	return {current, 0}
+end
+
+local remaining = redis.call("DECRBY", KEYS[1], n)
+return {remaining, 1}
+`)
+
+// TakeN debits n units from key's bucket in one shot, atomically, so a batch
+// either runs against the quota it had when it started or not at all — unlike
+// n sequential Take calls, it can't let part of a batch through before the
+// quota runs out. It reports how many units were available when the request
+// was denied, so callers can surface the shortfall.
+func (s *RedisStore) TakeN(key string, n, max int, expiration time.Duration) (available int, allowed bool, err error) {
+	res, err := takeNScript.Run(database.Ctx, s.client, []string{key}, n, max, int(expiration.Seconds())).Result()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return unpackPair(res)
+}
+
+func unpackPair(res interface{}) (int, bool, error) {
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return 0, false, errors.New("ratelimit: unexpected script result")
+	}
+
+	available, ok := vals[0].(int64)
+	if !ok {
+		return 0, false, errors.New("ratelimit: unexpected script result")
+	}
+
+	allowed, ok := vals[1].(int64)
+	if !ok {
+		return 0, false, errors.New("ratelimit: unexpected script result")
+	}
+
+	return int(available), allowed == 1, nil
+}
+
+func unpackTriple(res interface{}) (int, int, bool, error) {
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 3 {
+		return 0, 0, false, errors.New("ratelimit: unexpected script result")
+	}
+
+	remaining, ok := vals[0].(int64)
+	if !ok {
+		return 0, 0, false, errors.New("ratelimit: unexpected script result")
+	}
+
+	ttl, ok := vals[1].(int64)
+	if !ok {
+		return 0, 0, false, errors.New("ratelimit: unexpected script result")
+	}
+
+	allowed, ok := vals[2].(int64)
+	if !ok {
+		return 0, 0, false, errors.New("ratelimit: unexpected script result")
+	}
+
+	return int(remaining), int(ttl), allowed == 1, nil
+}